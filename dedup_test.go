@@ -0,0 +1,76 @@
+package event_emitter
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventEmitter_Dedup(t *testing.T) {
+	t.Run("duplicate id within TTL is dropped", func(t *testing.T) {
+		var em = New[Subscriber[any]](&Config{DedupTTL: 100 * time.Millisecond})
+		var hits int64
+
+		suber1 := em.NewSubscriber()
+		em.Subscribe(suber1, "topic1", func(subscriber Subscriber[any], msg any) error {
+			atomic.AddInt64(&hits, 1)
+			return nil
+		})
+
+		em.PublishID("topic1", "msg-1", "hello")
+		em.PublishID("topic1", "msg-1", "hello-retry")
+		assert.EqualValues(t, 1, atomic.LoadInt64(&hits))
+	})
+
+	t.Run("id is deliverable again after TTL expires", func(t *testing.T) {
+		var em = New[Subscriber[any]](&Config{DedupTTL: 10 * time.Millisecond})
+		var hits int64
+
+		suber1 := em.NewSubscriber()
+		em.Subscribe(suber1, "topic1", func(subscriber Subscriber[any], msg any) error {
+			atomic.AddInt64(&hits, 1)
+			return nil
+		})
+
+		em.PublishID("topic1", "msg-1", "hello")
+		time.Sleep(20 * time.Millisecond)
+		em.PublishID("topic1", "msg-1", "hello-again")
+		assert.EqualValues(t, 2, atomic.LoadInt64(&hits))
+	})
+
+	t.Run("same id on a different topic sharing the bucket is not treated as duplicate", func(t *testing.T) {
+		var em = New[Subscriber[any]](&Config{BucketNum: 1, DedupTTL: 100 * time.Millisecond})
+		var hits int64
+
+		suber1 := em.NewSubscriber()
+		em.Subscribe(suber1, "topic1", func(subscriber Subscriber[any], msg any) error {
+			atomic.AddInt64(&hits, 1)
+			return nil
+		})
+		em.Subscribe(suber1, "topic2", func(subscriber Subscriber[any], msg any) error {
+			atomic.AddInt64(&hits, 1)
+			return nil
+		})
+
+		em.PublishID("topic1", "msg-1", "hello")
+		em.PublishID("topic2", "msg-1", "hello")
+		assert.EqualValues(t, 2, atomic.LoadInt64(&hits))
+	})
+
+	t.Run("DedupTTL of zero disables deduplication", func(t *testing.T) {
+		var em = New[Subscriber[any]](nil)
+		var hits int64
+
+		suber1 := em.NewSubscriber()
+		em.Subscribe(suber1, "topic1", func(subscriber Subscriber[any], msg any) error {
+			atomic.AddInt64(&hits, 1)
+			return nil
+		})
+
+		em.PublishID("topic1", "msg-1", "hello")
+		em.PublishID("topic1", "msg-1", "hello-again")
+		assert.EqualValues(t, 2, atomic.LoadInt64(&hits))
+	})
+}
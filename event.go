@@ -1,15 +1,30 @@
 package event_emitter
 
 import (
+	"encoding/json"
 	"fmt"
 	"hash/maphash"
 	"math/rand"
 	"strings"
 	"sync"
+	"time"
 )
 
 const subTopic = "sub-topic-"
 
+// subSubject 是订阅者 Metadata 里记录每个主题已订阅subject集合的key前缀
+// subSubject prefixes the Metadata key recording the subjects a subscriber used on a topic
+const subSubject = "sub-subject-"
+
+// allSubjects 代表"所有subject"的哨兵值
+// allSubjects is the sentinel meaning "all subjects"
+const allSubjects = ""
+
+// subSlowConsumerOnce 是订阅者 Metadata 里保存其专属 sync.Once 的key, 用来保证 OnSlowConsumer 只触发一次
+// subSlowConsumerOnce is the Metadata key holding a subscriber's own sync.Once, ensuring
+// OnSlowConsumer fires at most once per subscriber
+const subSlowConsumerOnce = "sub-slow-consumer-once"
+
 type Config struct {
 	// 分片数
 	// Number of slices
@@ -18,6 +33,39 @@ type Config struct {
 	// 每个分片里主题表的初始化容量, 根据主题订阅量估算, 默认为0.
 	// The initialization capacity of the topic table in each slice is estimated based on the topic subscriptions and is 0 by default.
 	BucketSize int64
+
+	// 每个主题保留的历史消息条数, 用于 SubscribeFrom 的断点续传. 默认为0, 即不保留历史消息.
+	// The number of historical messages retained per topic, used for SubscribeFrom resume.
+	// Defaults to 0, meaning no history is retained.
+	HistorySize int64
+
+	// 每个订阅者投递队列的容量. 默认为0, 即回调在发布协程里同步执行(原有行为).
+	// The capacity of each subscriber's delivery queue. Defaults to 0, meaning the callback
+	// runs synchronously on the publishing goroutine (the original behavior).
+	DeliveryQueueSize int64
+
+	// 队列满载时的默认处理策略, 可在 Subscribe/SubscribeFrom 时按订阅者覆盖. 默认为 DropOldest.
+	// The default policy applied when a queue is full; can be overridden per subscriber in
+	// Subscribe/SubscribeFrom. Defaults to DropOldest.
+	DeliveryPolicy DeliveryPolicy
+
+	// BlockWithTimeout 策略下, 发布协程等待队列腾出空间的最长时间. 默认不设上限.
+	// Under the BlockWithTimeout policy, how long the publishing goroutine waits for room in
+	// the queue. Unbounded by default.
+	DeliveryTimeout time.Duration
+
+	// 跨进程的消息传输层, 默认为 LocalTransport, 即只在本进程内投递.
+	// The cross-process message transport. Defaults to LocalTransport, i.e. delivery stays
+	// within this process.
+	Transport Transport
+
+	// 跨进程消息的编解码器, 默认是 JSONCodec.
+	// The codec used to encode/decode messages that cross the network. Defaults to JSONCodec.
+	Codec Codec
+
+	// PublishID 去重窗口的大小. 默认为0, 即不做去重.
+	// The size of the PublishID dedup window. Defaults to 0, meaning no deduplication.
+	DedupTTL time.Duration
 }
 
 func (c *Config) init() {
@@ -28,12 +76,42 @@ func (c *Config) init() {
 		c.BucketSize = 0
 	}
 	c.BucketNum = toBinaryNumber(c.BucketNum)
+	if c.Transport == nil {
+		c.Transport = NewLocalTransport()
+	}
+	if c.Codec == nil {
+		c.Codec = JSONCodec{}
+	}
 }
 
 type EventEmitter[T Subscriber[T]] struct {
-	conf    Config
-	seed    maphash.Seed
-	buckets []*bucket[T]
+	conf           Config
+	seed           maphash.Seed
+	buckets        []*bucket[T]
+	wild           *topicTrie[T]
+	onSlowConsumer func(suber T)
+	nodeID         string
+
+	// slowConsumerMu 保护惰性创建订阅者专属 sync.Once 并存入其 Metadata 这一步
+	// slowConsumerMu guards the lazy creation of a subscriber's own sync.Once in its Metadata
+	slowConsumerMu sync.Mutex
+
+	// subjectLocks 按订阅者ID哈希分片, 保护订阅者 Metadata 里 subSubject 集合的读改写, 分片方式与
+	// getBucket 一致.
+	// subjectLocks is sharded by a hash of the subscriber ID to guard the subSubject set in a
+	// subscriber's Metadata, sharded the same way getBucket is.
+	subjectLocks [subjectLockShards]sync.Mutex
+}
+
+// subjectLockShards 是 subjectLocks 的分片数, 必须是2的幂
+// subjectLockShards is the shard count for subjectLocks; must be a power of two
+const subjectLockShards = 32
+
+// subjectLock 返回某个订阅者专属的subject集合锁.
+// subjectLock returns the subject-set lock dedicated to a given subscriber.
+func (c *EventEmitter[T]) subjectLock(suberID string) *sync.Mutex {
+	i := maphash.String(c.seed, suberID) & (subjectLockShards - 1)
+	return &c.subjectLocks[i]
 }
 
 // New 创建事件发射器实例
@@ -47,17 +125,41 @@ func New[T Subscriber[T]](conf *Config) *EventEmitter[T] {
 	buckets := make([]*bucket[T], 0, conf.BucketNum)
 	for i := int64(0); i < conf.BucketNum; i++ {
 		buckets = append(buckets, &bucket[T]{
-			Mutex:  sync.Mutex{},
-			Size:   conf.BucketSize,
-			Topics: make(map[string]*topicField[T]),
+			Mutex:       sync.Mutex{},
+			Size:        conf.BucketSize,
+			HistorySize: conf.HistorySize,
+			DedupTTL:    conf.DedupTTL,
+			Topics:      make(map[string]*topicField[T]),
+			dedup:       make(map[string]time.Time),
 		})
 	}
 
-	return &EventEmitter[T]{
+	em := &EventEmitter[T]{
 		conf:    *conf,
 		seed:    maphash.MakeSeed(),
 		buckets: buckets,
+		wild:    newTopicTrie[T](),
+		nodeID:  fmt.Sprintf("%d", rand.Int63()),
+	}
+
+	em.conf.Transport.Dispatch(em.receiveFromTransport)
+
+	return em
+}
+
+// receiveFromTransport 处理从 Transport 收到的跨进程消息, 丢弃自己发布的回环消息
+// receiveFromTransport handles a cross-process message, dropping echoes of our own publish
+func (c *EventEmitter[T]) receiveFromTransport(topic string, data []byte) {
+	var envelope transportEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.Origin == c.nodeID {
+		return
+	}
+	var msg any
+	if err := c.conf.Codec.Decode(envelope.Payload, &msg); err != nil {
+		return
 	}
+	c.getBucket(topic).publish(topic, envelope.Subject, msg)
+	c.wild.publish(topic, msg)
 }
 
 // NewSubscriber 生成订阅ID. 也可以使用自己的ID, 保证唯一即可.
@@ -74,30 +176,206 @@ func (c *EventEmitter[T]) getBucket(topic string) *bucket[T] {
 	return c.buckets[i]
 }
 
-// Publish 向主题发布消息
-// Publish a message to the topic
+// OnSlowConsumer 设置 Disconnect 策略触发时的回调, 此时订阅者已被取消所有订阅, 每个订阅者只触发一次.
+// OnSlowConsumer sets the callback invoked when the Disconnect policy trips; the subscriber has
+// already been unsubscribed from everything, and this fires at most once per subscriber.
+func (c *EventEmitter[T]) OnSlowConsumer(f func(suber T)) {
+	c.onSlowConsumer = f
+}
+
+// wrapDelivery 在 Config.DeliveryQueueSize 大于0时, 把用户回调包装成经由该订阅者专属队列投递的回调.
+// wrapDelivery wraps the user callback to deliver through the subscriber's own queue when
+// Config.DeliveryQueueSize is greater than 0.
+func (c *EventEmitter[T]) wrapDelivery(suber T, f func(subscriber T, msg any) error, policy []DeliveryPolicy) (eventCallback[T], *deliveryQueue[T]) {
+	p := c.conf.DeliveryPolicy
+	if len(policy) > 0 {
+		p = policy[0]
+	}
+	q := newDeliveryQueue[T](c.conf.DeliveryQueueSize, p, c.conf.DeliveryTimeout, func() {
+		c.UnSubscribeAll(suber)
+		c.slowConsumerOnce(suber).Do(func() {
+			if c.onSlowConsumer != nil {
+				c.onSlowConsumer(suber)
+			}
+		})
+	})
+	cb := func(s T, msg any) error {
+		q.enqueue(deliveryTask[T]{suber: s, msg: msg, cb: f})
+		return nil
+	}
+	return cb, q
+}
+
+// slowConsumerOnce 返回这个订阅者专属的 sync.Once, 首次调用时创建并存入其 Metadata
+// slowConsumerOnce returns this subscriber's own sync.Once, creating it on first use
+func (c *EventEmitter[T]) slowConsumerOnce(suber T) *sync.Once {
+	c.slowConsumerMu.Lock()
+	defer c.slowConsumerMu.Unlock()
+
+	md := suber.GetMetadata()
+	if v, ok := md.Load(subSlowConsumerOnce); ok {
+		return v.(*sync.Once)
+	}
+	once := new(sync.Once)
+	md.Store(subSlowConsumerOnce, once)
+	return once
+}
+
+// Publish 向主题发布消息, 等价于 PublishWithSubject(topic, "", msg)
+// Publish a message to the topic; equivalent to PublishWithSubject(topic, "", msg)
 func (c *EventEmitter[T]) Publish(topic string, msg any) {
-	c.getBucket(topic).publish(topic, msg)
+	c.PublishWithSubject(topic, allSubjects, msg)
 }
 
+// PublishWithSubject 向主题的某个subject发布消息, 通配符订阅者始终收到. 同时经 Config.Transport 转发.
+// PublishWithSubject publishes to one subject of the topic; wildcard subscribers always receive
+// it. Also forwarded to other processes via Config.Transport.
+func (c *EventEmitter[T]) PublishWithSubject(topic, subject string, msg any) {
+	c.getBucket(topic).publish(topic, subject, msg)
+	c.wild.publish(topic, msg)
+	c.forward(topic, subject, msg)
+}
+
+// forward 把消息编码后交给 Transport 发往其它进程
+// forward encodes the message and hands it to the Transport to reach other processes
+func (c *EventEmitter[T]) forward(topic, subject string, msg any) {
+	payload, err := c.conf.Codec.Encode(msg)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(transportEnvelope{Origin: c.nodeID, Subject: subject, Payload: payload})
+	if err != nil {
+		return
+	}
+	_ = c.conf.Transport.Publish(topic, data)
+}
+
+// PublishID 发布一条携带去重ID的消息. 若该ID在 Config.DedupTTL 窗口内已出现过, 本次发布会被直接丢弃.
+// PublishID publishes a message carrying a dedup ID; if seen within Config.DedupTTL, it is dropped.
+func (c *EventEmitter[T]) PublishID(topic, msgID string, msg any) {
+	if c.getBucket(topic).checkDuplicate(topic, msgID) {
+		return
+	}
+	c.PublishWithSubject(topic, allSubjects, msg)
+}
+
+// PublishE 向主题发布消息, checkSent 判断是否跳过某订阅者, 回调的 error 连同订阅者一起交给 f.
+// 不会转发给 Config.Transport.
+// PublishE publishes to the topic; checkSent decides whether to skip a subscriber, and f
+// receives each callback's error. Not forwarded to Config.Transport.
 func (c *EventEmitter[T]) PublishE(topic string, msg any,
 	checkSent func(subscriber T) bool,
 	f func(subscriber T, err error)) {
 	c.getBucket(topic).publish_e(topic, msg, checkSent, f)
+	c.wild.publishE(topic, msg, checkSent, f)
 }
 
-// Subscribe 订阅主题消息. 注意: 回调函数必须是非阻塞的.
-// Subscribe messages from the topic. Note: Callback functions must be non-blocking.
-func (c *EventEmitter[T]) Subscribe(suber T, topic string, f func(subscriber T, msg any) error) {
+// Subscribe 订阅主题消息. 注意: 回调函数必须是非阻塞的. 主题中包含 `+`/`#` 时按层级订阅到通配符树,
+// 否则走精确匹配的分片表. 通配符订阅的跨进程投递有限制, 见 SubscribeWithSubject 的说明.
+// Subscribe messages from the topic. Note: Callback functions must be non-blocking. A topic
+// containing `+`/`#` is routed to the wildcard trie; otherwise it uses the exact-match sharded
+// table. Cross-process delivery to wildcard subscriptions is limited, see SubscribeWithSubject.
+func (c *EventEmitter[T]) Subscribe(suber T, topic string, f func(subscriber T, msg any) error, policy ...DeliveryPolicy) {
+	c.SubscribeWithSubject(suber, topic, allSubjects, f, policy...)
+}
+
+// SubscribeWithSubject 订阅主题消息, 只接收发布到该subject(或空subject即"所有subject")的消息. 注意:
+// 回调函数必须是非阻塞的. 通配符主题不按subject分区, 会收到该主题下的所有消息. 通配符订阅不会调用
+// Config.Transport.AddSubscriber(通配符模式无法映射到底层broker的精确匹配订阅), 因此跨进程场景下
+// 通配符订阅者只能收到本进程也有精确主题订阅的那些主题上转发来的消息, 精确主题订阅不受此限制.
+// SubscribeWithSubject subscribes to the topic, only receiving messages published to that
+// subject (or the empty "all subjects" subject). Note: callbacks must be non-blocking. A
+// wildcard topic isn't partitioned by subject and receives every message on it. Wildcard
+// subscriptions never call Config.Transport.AddSubscriber (a wildcard pattern doesn't map onto
+// the broker's exact-match semantics), so in a cross-process setup they only receive messages
+// forwarded from other processes on topics that also have an exact-topic subscription in this
+// process; exact-topic subscriptions are unaffected.
+func (c *EventEmitter[T]) SubscribeWithSubject(suber T, topic, subject string, f func(subscriber T, msg any) error, policy ...DeliveryPolicy) {
+	suber.GetMetadata().Store(subTopic+topic, topic)
+	c.addSubject(suber, topic, subject)
+	ele := c.newTopicElement(suber, f, policy)
+	if isWildcardTopic(topic) {
+		c.wild.subscribe(suber, topic, ele)
+		return
+	}
+	_ = c.conf.Transport.AddSubscriber(topic)
+	c.getBucket(topic).subscribe(suber, topic, subject, ele)
+}
+
+// SubscribeFrom 订阅主题消息, 先重放历史消息中序号严格大于 lastEventID 的部分(lastEventID 为0则重放全部),
+// 重放结束后以 HistoryDispatched{} 回调一次, 之后才是实时消息. 需要 Config.HistorySize 开启历史缓冲.
+// SubscribeFrom subscribes to the topic, first replaying history strictly after lastEventID (or
+// the whole buffer if 0), then firing HistoryDispatched{} once before switching to live
+// messages. Requires Config.HistorySize to be set.
+func (c *EventEmitter[T]) SubscribeFrom(suber T, topic string, lastEventID uint64, f func(subscriber T, msg any) error, policy ...DeliveryPolicy) {
 	suber.GetMetadata().Store(subTopic+topic, topic)
-	c.getBucket(topic).subscribe(suber, topic, f)
+	c.addSubject(suber, topic, allSubjects)
+	ele := c.newTopicElement(suber, f, policy)
+	_ = c.conf.Transport.AddSubscriber(topic)
+	c.getBucket(topic).subscribeFrom(suber, topic, lastEventID, f, ele)
+}
+
+func (c *EventEmitter[T]) newTopicElement(suber T, f func(subscriber T, msg any) error, policy []DeliveryPolicy) topicElement[T] {
+	if c.conf.DeliveryQueueSize <= 0 {
+		return topicElement[T]{suber: suber, cb: f}
+	}
+	cb, q := c.wrapDelivery(suber, f, policy)
+	return topicElement[T]{suber: suber, cb: cb, queue: q}
 }
 
 // UnSubscribe 取消订阅一个主题
 // Cancel a subscribed topic
 func (c *EventEmitter[T]) UnSubscribe(suber T, topic string) {
+	subjects := c.popSubjects(suber, topic)
 	suber.GetMetadata().Delete(subTopic + topic)
-	c.getBucket(topic).unSubscribe(suber, topic)
+	if isWildcardTopic(topic) {
+		c.wild.unSubscribe(suber, topic)
+		return
+	}
+	for _, subject := range subjects {
+		c.getBucket(topic).unSubscribe(suber, topic, subject)
+	}
+	if c.getBucket(topic).countTopicSubscriber(topic) == 0 {
+		_ = c.conf.Transport.RemoveSubscriber(topic)
+	}
+}
+
+// addSubject 把一次 SubscribeWithSubject 用到的subject记入该订阅者在这个主题上的subject集合.
+// 用slice去重而不是map, 因为同一主题上的subject数量通常很小.
+// addSubject records the subject used by one SubscribeWithSubject call into this subscriber's
+// subject set for the topic. Dedups with a slice rather than a map since the set is usually tiny.
+func (c *EventEmitter[T]) addSubject(suber T, topic, subject string) {
+	lock := c.subjectLock(suber.GetSubscriberID())
+	lock.Lock()
+	defer lock.Unlock()
+
+	md := suber.GetMetadata()
+	key := subSubject + topic
+	v, _ := md.Load(key)
+	subjects, _ := v.([]string)
+	for _, s := range subjects {
+		if s == subject {
+			return
+		}
+	}
+	md.Store(key, append(subjects, subject))
+}
+
+// popSubjects 读取并清除某个主题订阅时记录的全部subject
+// popSubjects reads and clears every subject recorded for a topic subscription
+func (c *EventEmitter[T]) popSubjects(suber T, topic string) []string {
+	lock := c.subjectLock(suber.GetSubscriberID())
+	lock.Lock()
+	defer lock.Unlock()
+
+	md := suber.GetMetadata()
+	key := subSubject + topic
+	v, ok := md.Load(key)
+	if !ok {
+		return nil
+	}
+	md.Delete(key)
+	return v.([]string)
 }
 
 // UnSubscribeAll 取消订阅所有主题
@@ -112,8 +390,18 @@ func (c *EventEmitter[T]) UnSubscribeAll(suber T) {
 		return true
 	})
 	for _, topic := range topics {
+		subjects := c.popSubjects(suber, topic)
 		md.Delete(subTopic + topic)
-		c.getBucket(topic).unSubscribe(suber, topic)
+		if isWildcardTopic(topic) {
+			c.wild.unSubscribe(suber, topic)
+			continue
+		}
+		for _, subject := range subjects {
+			c.getBucket(topic).unSubscribe(suber, topic, subject)
+		}
+		if c.getBucket(topic).countTopicSubscriber(topic) == 0 {
+			_ = c.conf.Transport.RemoveSubscriber(topic)
+		}
 	}
 }
 
@@ -130,9 +418,13 @@ func (c *EventEmitter[T]) GetTopicsBySubscriber(suber T) []string {
 	return topics
 }
 
-// CountSubscriberByTopic 获取主题订阅人数
-// Get the number of subscribers to a topic
+// CountSubscriberByTopic 获取主题订阅人数. topic 既可以是具体主题, 也可以是通配符订阅模式(如 `chat/+/news`).
+// Get the number of subscribers to a topic. topic may be a concrete topic or a wildcard
+// subscription pattern (e.g. `chat/+/news`).
 func (c *EventEmitter[T]) CountSubscriberByTopic(topic string) int {
+	if isWildcardTopic(topic) {
+		return c.wild.countByPattern(topic)
+	}
 	return c.getBucket(topic).countTopicSubscriber(topic)
 }
 
@@ -146,8 +438,15 @@ func (c *EventEmitter[T]) TopicStatus() []*TopicStatus {
 
 type bucket[T Subscriber[T]] struct {
 	sync.Mutex
-	Size   int64
-	Topics map[string]*topicField[T]
+	Size        int64
+	HistorySize int64
+	Topics      map[string]*topicField[T]
+
+	// DedupTTL 大于0时, 该分片下所有主题共用下面这套去重状态
+	// When DedupTTL is greater than 0, every topic in this shard shares the dedup state below
+	DedupTTL   time.Duration
+	dedup      map[string]time.Time
+	dedupQueue []dedupEntry
 }
 
 type TopicStatus struct {
@@ -155,23 +454,29 @@ type TopicStatus struct {
 	Count int
 }
 
+// newTopicField 创建一个空的 topicField, subers 按需惰性创建各 subject 分组
+// newTopicField creates an empty topicField; subers groups are created lazily per subject
+func newTopicField[T Subscriber[T]]() *topicField[T] {
+	return &topicField[T]{subers: make(map[string]map[string]topicElement[T])}
+}
+
 // 新增订阅
-func (c *bucket[T]) subscribe(suber T, topic string, f eventCallback[T]) {
+func (c *bucket[T]) subscribe(suber T, topic, subject string, ele topicElement[T]) {
 	c.Lock()
 	defer c.Unlock()
 
-	subId := suber.GetSubscriberID()
-	ele := topicElement[T]{suber: suber, cb: f}
-
 	t, ok := c.Topics[topic]
 	if !ok {
-		t = &topicField[T]{subers: make(map[string]topicElement[T], c.Size)}
-		t.subers[subId] = ele
+		t = newTopicField[T]()
 		c.Topics[topic] = t
-		return
 	}
 
-	t.subers[subId] = ele
+	group, ok := t.subers[subject]
+	if !ok {
+		group = make(map[string]topicElement[T], c.Size)
+		t.subers[subject] = group
+	}
+	group[suber.GetSubscriberID()] = ele
 }
 
 func (c *bucket[T]) TopicStatus() []*TopicStatus {
@@ -180,35 +485,113 @@ func (c *bucket[T]) TopicStatus() []*TopicStatus {
 
 	var status []*TopicStatus
 	for topic, t := range c.Topics {
-		status = append(status, &TopicStatus{Topic: topic, Count: len(t.subers)})
+		count := 0
+		for _, group := range t.subers {
+			count += len(group)
+		}
+		status = append(status, &TopicStatus{Topic: topic, Count: count})
 	}
 	return status
 }
 
-func (c *bucket[T]) publish(topic string, msg any) {
+// publish 把消息投递给以 subject 及空subject订阅的订阅者. 回调在释放分片锁之后才调用, 避免
+// BlockWithTimeout 策略下的慢订阅者拖慢同一分片里的其它主题.
+// publish delivers the message to subscribers subscribed with subject or the empty "all
+// subjects" subject. Callbacks run only after the bucket lock is released, so a subscriber
+// blocked under the BlockWithTimeout policy can't stall other topics in this bucket.
+func (c *bucket[T]) publish(topic, subject string, msg any) {
 	c.Lock()
-	defer c.Unlock()
 
 	t, ok := c.Topics[topic]
 	if !ok {
-		return
+		if c.HistorySize <= 0 {
+			c.Unlock()
+			return
+		}
+		t = newTopicField[T]()
+		c.Topics[topic] = t
+	}
+
+	if c.HistorySize > 0 {
+		t.historySeq++
+		t.history = append(t.history, historyEntry{id: t.historySeq, msg: msg})
+		if int64(len(t.history)) > c.HistorySize {
+			t.history = t.history[1:]
+		}
 	}
-	for _, v := range t.subers {
+
+	var recipients []topicElement[T]
+	for _, v := range t.subers[subject] {
+		recipients = append(recipients, v)
+	}
+	if subject != allSubjects {
+		for _, v := range t.subers[allSubjects] {
+			recipients = append(recipients, v)
+		}
+	}
+	c.Unlock()
+
+	for _, v := range recipients {
 		v.cb(v.suber, msg)
 	}
 }
 
+// subscribeFrom 重放历史消息中序号严格大于 lastEventID 的部分, 再接入实时投递(挂在空subject分组下)
+// subscribeFrom replays history strictly after lastEventID, then attaches the subscriber to
+// live delivery under the empty "all subjects" group
+func (c *bucket[T]) subscribeFrom(suber T, topic string, lastEventID uint64, replay eventCallback[T], ele topicElement[T]) {
+	c.Lock()
+	defer c.Unlock()
+
+	t, ok := c.Topics[topic]
+	if !ok {
+		t = newTopicField[T]()
+		c.Topics[topic] = t
+	}
+
+	start := 0
+	if lastEventID != 0 {
+		start = len(t.history)
+		for i, entry := range t.history {
+			if entry.id > lastEventID {
+				start = i
+				break
+			}
+		}
+	}
+	for _, entry := range t.history[start:] {
+		replay(suber, entry.msg)
+	}
+	replay(suber, HistoryDispatched{})
+
+	group, ok := t.subers[allSubjects]
+	if !ok {
+		group = make(map[string]topicElement[T], c.Size)
+		t.subers[allSubjects] = group
+	}
+	group[suber.GetSubscriberID()] = ele
+}
+
+// publish_e 和 publish 一样, 只在释放分片锁之后才调用回调
+// publish_e, like publish, only invokes callbacks after releasing the bucket lock
 func (c *bucket[T]) publish_e(topic string, msg any,
 	checkSent func(subscriber T) bool,
 	f func(subscriber T, err error)) {
 	c.Lock()
-	defer c.Unlock()
-
 	t, ok := c.Topics[topic]
 	if !ok {
+		c.Unlock()
 		return
 	}
-	for _, v := range t.subers {
+	var recipients []topicElement[T]
+	for _, group := range t.subers {
+		for _, v := range group {
+			recipients = append(recipients, v)
+		}
+	}
+	c.Unlock()
+
+	for _, v := range recipients {
 		if !checkSent(v.suber) {
 			err := v.cb(v.suber, msg)
 			f(v.suber, err)
@@ -217,13 +600,27 @@ func (c *bucket[T]) publish_e(topic string, msg any,
 }
 
 // 取消某个主题的订阅
-func (c *bucket[T]) unSubscribe(suber T, topic string) {
+func (c *bucket[T]) unSubscribe(suber T, topic, subject string) {
 	c.Lock()
 	defer c.Unlock()
 
 	v, ok := c.Topics[topic]
-	if ok {
-		delete(v.subers, suber.GetSubscriberID())
+	if !ok {
+		return
+	}
+	group, ok := v.subers[subject]
+	if !ok {
+		return
+	}
+	id := suber.GetSubscriberID()
+	if ele, exist := group[id]; exist && ele.queue != nil {
+		// close 可能要等一个阻塞中的 enqueue 先结束, 异步调用以免卡住分片锁
+		// close may wait out an in-flight enqueue, so run it async to avoid blocking the lock
+		go ele.queue.close()
+	}
+	delete(group, id)
+	if len(group) == 0 {
+		delete(v.subers, subject)
 	}
 }
 
@@ -235,7 +632,44 @@ func (c *bucket[T]) countTopicSubscriber(topic string) int {
 	if !exists {
 		return 0
 	}
-	return len(v.subers)
+	count := 0
+	for _, group := range v.subers {
+		count += len(group)
+	}
+	return count
+}
+
+// checkDuplicate 清扫过期的去重记录, 再判断 (topic, msgID) 是否在 DedupTTL 窗口内被见过. key 带上
+// topic 是因为一个分片被多个topic共用, 避免偶然重名的msgID被互相当成重复.
+// checkDuplicate sweeps expired dedup entries, then reports whether (topic, msgID) has been seen
+// within the DedupTTL window. The key includes topic since a bucket is shared by many topics.
+func (c *bucket[T]) checkDuplicate(topic, msgID string) bool {
+	if c.DedupTTL <= 0 || msgID == "" {
+		return false
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	now := time.Now()
+	key := topic + "\x00" + msgID
+
+	for len(c.dedupQueue) > 0 && !now.Before(c.dedupQueue[0].expiresAt) {
+		front := c.dedupQueue[0]
+		c.dedupQueue = c.dedupQueue[1:]
+		if exp, ok := c.dedup[front.id]; ok && exp.Equal(front.expiresAt) {
+			delete(c.dedup, front.id)
+		}
+	}
+
+	if exp, ok := c.dedup[key]; ok && now.Before(exp) {
+		return true
+	}
+
+	expiresAt := now.Add(c.DedupTTL)
+	c.dedup[key] = expiresAt
+	c.dedupQueue = append(c.dedupQueue, dedupEntry{id: key, expiresAt: expiresAt})
+	return false
 }
 
 func toBinaryNumber(n int64) int64 {
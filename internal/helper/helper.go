@@ -0,0 +1,18 @@
+package helper
+
+import "math/rand"
+
+var Numeric = rand.New(rand.NewSource(1))
+
+func Uniq[T comparable](list []T) []T {
+	seen := make(map[T]struct{}, len(list))
+	result := make([]T, 0, len(list))
+	for _, v := range list {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
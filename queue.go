@@ -0,0 +1,134 @@
+package event_emitter
+
+import (
+	"sync"
+	"time"
+)
+
+// DeliveryPolicy 决定订阅者的投递队列在满载时的行为
+// DeliveryPolicy decides how a subscriber's delivery queue behaves once it is full
+type DeliveryPolicy uint8
+
+const (
+	// DropOldest 丢弃队列里最早的消息, 腾出空间给新消息
+	// DropOldest evicts the oldest queued message to make room for the new one
+	DropOldest DeliveryPolicy = iota
+
+	// DropNewest 丢弃这条新消息, 保留队列里已有的消息
+	// DropNewest drops the incoming message, keeping what is already queued
+	DropNewest
+
+	// BlockWithTimeout 阻塞发布协程直到队列腾出空间或者超过 Config.DeliveryTimeout
+	// BlockWithTimeout blocks the publishing goroutine until the queue has room or
+	// Config.DeliveryTimeout elapses
+	BlockWithTimeout
+
+	// Disconnect 队列已满时自动取消该订阅者的所有订阅, 并触发 OnSlowConsumer 钩子
+	// Disconnect auto-unsubscribes the subscriber from everything and fires the
+	// OnSlowConsumer hook once the queue is full
+	Disconnect
+)
+
+// deliveryTask 是排队等待投递给某个订阅者的一条消息
+// deliveryTask is one message queued for delivery to a subscriber
+type deliveryTask[T Subscriber[T]] struct {
+	suber T
+	msg   any
+	cb    eventCallback[T]
+}
+
+// deliveryQueue 是单个订阅背后的有界投递队列, 由一个专属的投递协程消费
+// deliveryQueue is the bounded queue behind a single subscription, drained by its own goroutine
+type deliveryQueue[T Subscriber[T]] struct {
+	mu         sync.RWMutex
+	ch         chan deliveryTask[T]
+	policy     DeliveryPolicy
+	timeout    time.Duration
+	onFull     func()
+	onFullOnce sync.Once
+	closeOnce  sync.Once
+	closed     bool
+}
+
+func newDeliveryQueue[T Subscriber[T]](size int64, policy DeliveryPolicy, timeout time.Duration, onFull func()) *deliveryQueue[T] {
+	if size <= 0 {
+		size = 1
+	}
+	q := &deliveryQueue[T]{
+		ch:      make(chan deliveryTask[T], size),
+		policy:  policy,
+		timeout: timeout,
+		onFull:  onFull,
+	}
+	go q.run()
+	return q
+}
+
+func (q *deliveryQueue[T]) run() {
+	for task := range q.ch {
+		task.cb(task.suber, task.msg)
+	}
+}
+
+// enqueue 按策略把任务放入队列, 满载时的处理方式取决于 q.policy, 发送由 q.mu 的读锁保护.
+// enqueue places the task on the queue per q.policy; the send is guarded by q.mu's read lock.
+func (q *deliveryQueue[T]) enqueue(task deliveryTask[T]) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return
+	}
+
+	switch q.policy {
+	case DropNewest:
+		select {
+		case q.ch <- task:
+		default:
+		}
+
+	case DropOldest:
+		for {
+			select {
+			case q.ch <- task:
+				return
+			default:
+				select {
+				case <-q.ch:
+				default:
+				}
+			}
+		}
+
+	case BlockWithTimeout:
+		if q.timeout <= 0 {
+			q.ch <- task
+			return
+		}
+		timer := time.NewTimer(q.timeout)
+		defer timer.Stop()
+		select {
+		case q.ch <- task:
+		case <-timer.C:
+		}
+
+	case Disconnect:
+		select {
+		case q.ch <- task:
+		default:
+			if q.onFull != nil {
+				q.onFullOnce.Do(func() { go q.onFull() })
+			}
+		}
+	}
+}
+
+// close 停止投递协程, 可并发调用多次, 只有第一次生效. 写锁会等进行中的 enqueue 先结束.
+// close stops the delivery goroutine; safe to call concurrently. Waits out any in-flight enqueue.
+func (q *deliveryQueue[T]) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closeOnce.Do(func() {
+		q.closed = true
+		close(q.ch)
+	})
+}
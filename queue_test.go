@@ -0,0 +1,148 @@
+package event_emitter
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventEmitter_DeliveryQueue(t *testing.T) {
+	t.Run("slow subscriber does not block other topics", func(t *testing.T) {
+		var em = New[Subscriber[any]](&Config{BucketNum: 1, DeliveryQueueSize: 8})
+
+		var blocker = make(chan struct{})
+		suber1 := em.NewSubscriber()
+		em.Subscribe(suber1, "slow", func(subscriber Subscriber[any], msg any) error {
+			<-blocker
+			return nil
+		})
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		suber2 := em.NewSubscriber()
+		em.Subscribe(suber2, "fast", func(subscriber Subscriber[any], msg any) error {
+			wg.Done()
+			return nil
+		})
+
+		em.Publish("slow", 1)
+		em.Publish("fast", 1)
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("fast subscriber was blocked by the slow one")
+		}
+		close(blocker)
+	})
+
+	t.Run("disconnect policy evicts slow consumer", func(t *testing.T) {
+		var em = New[Subscriber[any]](&Config{DeliveryQueueSize: 1, DeliveryPolicy: Disconnect})
+
+		var evicted int32
+		var evictedWg sync.WaitGroup
+		evictedWg.Add(1)
+		em.OnSlowConsumer(func(suber Subscriber[any]) {
+			atomic.StoreInt32(&evicted, 1)
+			evictedWg.Done()
+		})
+
+		var blocker = make(chan struct{})
+		suber1 := em.NewSubscriber()
+		em.Subscribe(suber1, "topic1", func(subscriber Subscriber[any], msg any) error {
+			<-blocker
+			return nil
+		})
+
+		for i := 0; i < 10; i++ {
+			em.Publish("topic1", i)
+		}
+
+		evictedWg.Wait()
+		assert.Equal(t, int32(1), atomic.LoadInt32(&evicted))
+		assert.Zero(t, em.CountSubscriberByTopic("topic1"))
+		close(blocker)
+	})
+
+	t.Run("OnSlowConsumer fires once even when multiple topics trip at once", func(t *testing.T) {
+		var em = New[Subscriber[any]](&Config{DeliveryQueueSize: 1, DeliveryPolicy: Disconnect})
+
+		var evicted int32
+		em.OnSlowConsumer(func(suber Subscriber[any]) {
+			atomic.AddInt32(&evicted, 1)
+		})
+
+		var blocker = make(chan struct{})
+		suber1 := em.NewSubscriber()
+		for _, topic := range []string{"topic1", "topic2", "topic3"} {
+			em.Subscribe(suber1, topic, func(subscriber Subscriber[any], msg any) error {
+				<-blocker
+				return nil
+			})
+		}
+
+		for _, topic := range []string{"topic1", "topic2", "topic3"} {
+			for i := 0; i < 10; i++ {
+				em.Publish(topic, i)
+			}
+		}
+
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(&evicted) > 0
+		}, time.Second, time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&evicted))
+		close(blocker)
+	})
+
+	t.Run("BlockWithTimeout on one topic does not block another topic in the same bucket", func(t *testing.T) {
+		var em = New[Subscriber[any]](&Config{BucketNum: 1, DeliveryQueueSize: 1, DeliveryPolicy: BlockWithTimeout})
+
+		var blocker = make(chan struct{})
+		suber1 := em.NewSubscriber()
+		em.Subscribe(suber1, "slow", func(subscriber Subscriber[any], msg any) error {
+			<-blocker
+			return nil
+		})
+
+		suber2 := em.NewSubscriber()
+		em.Subscribe(suber2, "fast", func(subscriber Subscriber[any], msg any) error {
+			return nil
+		})
+
+		em.Publish("slow", 1)
+		time.Sleep(20 * time.Millisecond) // delivery goroutine picks up task 1 and blocks on blocker
+		em.Publish("slow", 2)             // fills the size-1 queue while the goroutine is still busy
+
+		blockedPublishDone := make(chan struct{})
+		go func() {
+			em.Publish("slow", 3) // queue and goroutine both busy, blocks until blocker closes
+			close(blockedPublishDone)
+		}()
+		time.Sleep(20 * time.Millisecond) // let the goroutine above actually block inside enqueue
+
+		fastDone := make(chan struct{})
+		go func() {
+			em.Publish("fast", 1)
+			close(fastDone)
+		}()
+
+		select {
+		case <-fastDone:
+		case <-time.After(time.Second):
+			t.Fatal("fast publish was blocked by a slow subscriber sharing the same bucket")
+		}
+
+		close(blocker)
+		<-blockedPublishDone
+	})
+}
@@ -0,0 +1,14 @@
+package event_emitter
+
+// historyEntry 是重放缓冲区中的一条记录, id 是该主题内单调递增的发布序号
+// historyEntry is one record in the replay buffer; id is a sequence number monotonically
+// increasing within its topic
+type historyEntry struct {
+	id  uint64
+	msg any
+}
+
+// HistoryDispatched 是 SubscribeFrom 在重放完历史消息后传给回调的哨兵消息, 订阅者据此判断追赶已完成.
+// HistoryDispatched is the sentinel message SubscribeFrom passes to the callback once history
+// replay finishes, letting the subscriber detect that catch-up is complete.
+type HistoryDispatched struct{}
@@ -0,0 +1,71 @@
+package event_emitter
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventEmitter_SubscribeFrom(t *testing.T) {
+	t.Run("replay then live", func(t *testing.T) {
+		var em = New[Subscriber[any]](&Config{HistorySize: 10})
+		em.Publish("topic1", 1)
+		em.Publish("topic1", 2)
+		em.Publish("topic1", 3)
+
+		var mu sync.Mutex
+		var received []any
+		var caughtUp bool
+		suber1 := em.NewSubscriber()
+		em.SubscribeFrom(suber1, "topic1", 0, func(subscriber Subscriber[any], msg any) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if _, ok := msg.(HistoryDispatched); ok {
+				caughtUp = true
+				return nil
+			}
+			received = append(received, msg)
+			return nil
+		})
+
+		em.Publish("topic1", 4)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.True(t, caughtUp)
+		assert.Equal(t, received, []any{1, 2, 3, 4})
+	})
+
+	t.Run("resume from last event id", func(t *testing.T) {
+		var em = New[Subscriber[any]](&Config{HistorySize: 10})
+		em.Publish("topic1", "a")
+		em.Publish("topic1", "b")
+		em.Publish("topic1", "c")
+
+		var received []any
+		suber1 := em.NewSubscriber()
+		em.SubscribeFrom(suber1, "topic1", 2, func(subscriber Subscriber[any], msg any) error {
+			received = append(received, msg)
+			return nil
+		})
+
+		assert.Equal(t, received, []any{"c", HistoryDispatched{}})
+	})
+
+	t.Run("ring buffer eviction", func(t *testing.T) {
+		var em = New[Subscriber[any]](&Config{HistorySize: 2})
+		em.Publish("topic1", 1)
+		em.Publish("topic1", 2)
+		em.Publish("topic1", 3)
+
+		var received []any
+		suber1 := em.NewSubscriber()
+		em.SubscribeFrom(suber1, "topic1", 0, func(subscriber Subscriber[any], msg any) error {
+			received = append(received, msg)
+			return nil
+		})
+
+		assert.Equal(t, received, []any{2, 3, HistoryDispatched{}})
+	})
+}
@@ -0,0 +1,88 @@
+package event_emitter
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventEmitter_Subject(t *testing.T) {
+	t.Run("only matching subject is invoked", func(t *testing.T) {
+		var em = New[Subscriber[any]](nil)
+		var hits int64
+
+		suber1 := em.NewSubscriber()
+		em.SubscribeWithSubject(suber1, "service-health", "svc-a", func(subscriber Subscriber[any], msg any) error {
+			atomic.AddInt64(&hits, 1)
+			return nil
+		})
+
+		em.PublishWithSubject("service-health", "svc-b", "down")
+		time.Sleep(10 * time.Millisecond)
+		assert.EqualValues(t, 0, atomic.LoadInt64(&hits))
+
+		em.PublishWithSubject("service-health", "svc-a", "down")
+		time.Sleep(10 * time.Millisecond)
+		assert.EqualValues(t, 1, atomic.LoadInt64(&hits))
+	})
+
+	t.Run("all-subjects subscriber receives every subject", func(t *testing.T) {
+		var em = New[Subscriber[any]](nil)
+		var wg sync.WaitGroup
+		wg.Add(2)
+
+		suber1 := em.NewSubscriber()
+		em.Subscribe(suber1, "service-health", func(subscriber Subscriber[any], msg any) error {
+			wg.Done()
+			return nil
+		})
+
+		em.PublishWithSubject("service-health", "svc-a", "down")
+		em.PublishWithSubject("service-health", "svc-b", "down")
+		wg.Wait()
+	})
+
+	t.Run("plain Publish only reaches all-subjects subscribers", func(t *testing.T) {
+		var em = New[Subscriber[any]](nil)
+		var hits int64
+
+		suber1 := em.NewSubscriber()
+		em.SubscribeWithSubject(suber1, "service-health", "svc-a", func(subscriber Subscriber[any], msg any) error {
+			atomic.AddInt64(&hits, 1)
+			return nil
+		})
+
+		em.Publish("service-health", "down")
+		time.Sleep(10 * time.Millisecond)
+		assert.EqualValues(t, 0, atomic.LoadInt64(&hits))
+	})
+
+	t.Run("unsubscribe removes subject subscription", func(t *testing.T) {
+		var em = New[Subscriber[any]](nil)
+
+		suber1 := em.NewSubscriber()
+		em.SubscribeWithSubject(suber1, "service-health", "svc-a", func(subscriber Subscriber[any], msg any) error {
+			return nil
+		})
+		assert.Equal(t, 1, em.CountSubscriberByTopic("service-health"))
+
+		em.UnSubscribe(suber1, "service-health")
+		assert.Equal(t, 0, em.CountSubscriberByTopic("service-health"))
+	})
+
+	t.Run("unsubscribe all removes every subject a subscriber used on the same topic", func(t *testing.T) {
+		var em = New[Subscriber[any]](nil)
+
+		suber1 := em.NewSubscriber()
+		em.SubscribeWithSubject(suber1, "t", "A", func(subscriber Subscriber[any], msg any) error { return nil })
+		em.SubscribeWithSubject(suber1, "t", "B", func(subscriber Subscriber[any], msg any) error { return nil })
+		assert.Equal(t, 2, em.CountSubscriberByTopic("t"))
+
+		em.UnSubscribeAll(suber1)
+		assert.Zero(t, len(em.GetTopicsBySubscriber(suber1)))
+		assert.Equal(t, 0, em.CountSubscriberByTopic("t"))
+	})
+}
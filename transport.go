@@ -0,0 +1,72 @@
+package event_emitter
+
+import "encoding/json"
+
+// Transport 把本地的发布/订阅语义对接到跨进程的消息通道上. LocalTransport 是默认实现, 等价于原有的单进程行为.
+// Transport bridges local publish/subscribe semantics onto a cross-process message channel.
+// LocalTransport is the default, equivalent to the original single-process behavior.
+type Transport interface {
+	// Publish 把已编码的消息发送到对端
+	// Publish sends an already-encoded message to peers
+	Publish(topic string, payload []byte) error
+
+	// Dispatch 注册一个处理函数, 每当从对端收到消息时调用. 只应该调用一次.
+	// Dispatch registers a handler invoked whenever a message arrives from a peer. Called once.
+	Dispatch(handler func(topic string, payload []byte))
+
+	// AddSubscriber 告知传输层本地新增了一个该主题的订阅者. 只会为精确主题调用, 通配符订阅不触发.
+	// AddSubscriber tells the transport a local subscriber was added for this topic. Only called
+	// for exact topics; wildcard subscriptions never trigger this.
+	AddSubscriber(topic string) error
+
+	// RemoveSubscriber 告知传输层本地某个主题不再有订阅者. 和 AddSubscriber 一样只针对精确主题.
+	// RemoveSubscriber tells the transport this topic no longer has any local subscribers.
+	RemoveSubscriber(topic string) error
+}
+
+// Codec 负责把 msg any 编解码为跨进程传输的字节, 默认是JSON
+// Codec encodes/decodes msg any into bytes for cross-process transport. Defaults to JSON.
+type Codec interface {
+	Encode(v any) ([]byte, error)
+	Decode(data []byte, v any) error
+}
+
+// JSONCodec 是默认的 Codec 实现, 基于 encoding/json
+// JSONCodec is the default Codec implementation, backed by encoding/json
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Decode(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// transportEnvelope 包裹跨进程发送的消息, Origin 用来识别并丢弃回环消息, Subject 保留发布时的 subject
+// transportEnvelope wraps a message sent across processes; Origin identifies echoes of our own
+// publish, Subject carries the publish subject
+type transportEnvelope struct {
+	Origin  string `json:"origin"`
+	Subject string `json:"subject"`
+	Payload []byte `json:"payload"`
+}
+
+// LocalTransport 不对接任何外部broker, 各方法都是空操作. 这是 Config.Transport 的默认值.
+// LocalTransport does not talk to any external broker; its methods are no-ops. The default
+// value of Config.Transport.
+type LocalTransport struct{}
+
+// NewLocalTransport 创建一个不跨进程的传输层, 即原有的单进程行为
+// NewLocalTransport creates a transport that never crosses a process boundary
+func NewLocalTransport() *LocalTransport {
+	return &LocalTransport{}
+}
+
+func (t *LocalTransport) Publish(topic string, payload []byte) error { return nil }
+
+func (t *LocalTransport) Dispatch(handler func(topic string, payload []byte)) {}
+
+func (t *LocalTransport) AddSubscriber(topic string) error { return nil }
+
+func (t *LocalTransport) RemoveSubscriber(topic string) error { return nil }
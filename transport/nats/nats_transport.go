@@ -0,0 +1,78 @@
+// Package natstransport implements event_emitter.Transport on top of NATS subjects.
+// It lives in its own module so that depending on it (and therefore on
+// github.com/nats-io/nats.go) is opt-in: the root event_emitter module stays
+// dependency-free for consumers who only need LocalTransport.
+package natstransport
+
+import (
+	"sync"
+
+	event_emitter "github.com/lxzan/event_emitter"
+	"github.com/nats-io/nats.go"
+)
+
+var _ event_emitter.Transport = (*NATSTransport)(nil)
+
+// NATSTransport 基于 NATS 的 subject 实现跨进程扇出, 每个订阅主题对应一个 NATS subject.
+// NATSTransport implements cross-process fan-out on top of NATS subjects; each subscribed
+// topic maps to one NATS subject.
+type NATSTransport struct {
+	conn    *nats.Conn
+	mu      sync.Mutex
+	subs    map[string]*nats.Subscription
+	handler func(topic string, payload []byte)
+}
+
+// NewNATSTransport 用一个已经连接好的 nats.Conn 创建 NATSTransport
+// NewNATSTransport creates a NATSTransport on top of an already-connected nats.Conn
+func NewNATSTransport(conn *nats.Conn) *NATSTransport {
+	return &NATSTransport{
+		conn: conn,
+		subs: make(map[string]*nats.Subscription),
+	}
+}
+
+func (t *NATSTransport) Publish(topic string, payload []byte) error {
+	return t.conn.Publish(topic, payload)
+}
+
+func (t *NATSTransport) Dispatch(handler func(topic string, payload []byte)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handler = handler
+}
+
+func (t *NATSTransport) AddSubscriber(topic string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.subs[topic]; ok {
+		return nil
+	}
+
+	sub, err := t.conn.Subscribe(topic, func(msg *nats.Msg) {
+		t.mu.Lock()
+		handler := t.handler
+		t.mu.Unlock()
+		if handler != nil {
+			handler(msg.Subject, msg.Data)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	t.subs[topic] = sub
+	return nil
+}
+
+func (t *NATSTransport) RemoveSubscriber(topic string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sub, ok := t.subs[topic]
+	if !ok {
+		return nil
+	}
+	delete(t.subs, topic)
+	return sub.Unsubscribe()
+}
@@ -0,0 +1,87 @@
+// Package redistransport implements event_emitter.Transport on top of Redis Pub/Sub.
+// It lives in its own module so that depending on it (and therefore on
+// github.com/redis/go-redis/v9) is opt-in: the root event_emitter module stays
+// dependency-free for consumers who only need LocalTransport.
+package redistransport
+
+import (
+	"context"
+	"sync"
+
+	event_emitter "github.com/lxzan/event_emitter"
+	"github.com/redis/go-redis/v9"
+)
+
+var _ event_emitter.Transport = (*RedisTransport)(nil)
+
+// RedisTransport 基于 Redis Pub/Sub 实现跨进程扇出, 每个订阅主题对应一个 Redis channel.
+// RedisTransport implements cross-process fan-out on top of Redis Pub/Sub; each subscribed
+// topic maps to one Redis channel.
+type RedisTransport struct {
+	client  *redis.Client
+	ctx     context.Context
+	mu      sync.Mutex
+	subs    map[string]*redis.PubSub
+	handler func(topic string, payload []byte)
+}
+
+// NewRedisTransport 用一个已经建立好的 redis.Client 创建 RedisTransport
+// NewRedisTransport creates a RedisTransport on top of an already-configured redis.Client
+func NewRedisTransport(client *redis.Client) *RedisTransport {
+	return &RedisTransport{
+		client: client,
+		ctx:    context.Background(),
+		subs:   make(map[string]*redis.PubSub),
+	}
+}
+
+func (t *RedisTransport) Publish(topic string, payload []byte) error {
+	return t.client.Publish(t.ctx, topic, payload).Err()
+}
+
+func (t *RedisTransport) Dispatch(handler func(topic string, payload []byte)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handler = handler
+}
+
+func (t *RedisTransport) AddSubscriber(topic string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.subs[topic]; ok {
+		return nil
+	}
+
+	ps := t.client.Subscribe(t.ctx, topic)
+	if _, err := ps.Receive(t.ctx); err != nil {
+		_ = ps.Close()
+		return err
+	}
+	t.subs[topic] = ps
+
+	go func() {
+		for msg := range ps.Channel() {
+			t.mu.Lock()
+			handler := t.handler
+			t.mu.Unlock()
+			if handler != nil {
+				handler(msg.Channel, []byte(msg.Payload))
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (t *RedisTransport) RemoveSubscriber(topic string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ps, ok := t.subs[topic]
+	if !ok {
+		return nil
+	}
+	delete(t.subs, topic)
+	return ps.Close()
+}
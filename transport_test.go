@@ -0,0 +1,103 @@
+package event_emitter
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memTransport 是仅用于测试的内存传输层, 模拟多进程共享同一个broker的效果.
+// memTransport is an in-memory transport used only for tests, simulating multiple processes
+// sharing the same broker.
+type memTransport struct {
+	mu      sync.Mutex
+	handler func(topic string, payload []byte)
+	peers   []*memTransport
+	topics  map[string]bool
+}
+
+func newMemTransportGroup(n int) []*memTransport {
+	group := make([]*memTransport, n)
+	for i := range group {
+		group[i] = &memTransport{topics: make(map[string]bool)}
+	}
+	for i := range group {
+		for j := range group {
+			if i != j {
+				group[i].peers = append(group[i].peers, group[j])
+			}
+		}
+	}
+	return group
+}
+
+func (t *memTransport) Publish(topic string, payload []byte) error {
+	for _, peer := range t.peers {
+		peer.mu.Lock()
+		handler := peer.handler
+		subscribed := peer.topics[topic]
+		peer.mu.Unlock()
+		if subscribed && handler != nil {
+			handler(topic, payload)
+		}
+	}
+	return nil
+}
+
+func (t *memTransport) Dispatch(handler func(topic string, payload []byte)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handler = handler
+}
+
+func (t *memTransport) AddSubscriber(topic string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.topics[topic] = true
+	return nil
+}
+
+func (t *memTransport) RemoveSubscriber(topic string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.topics, topic)
+	return nil
+}
+
+func TestEventEmitter_Transport(t *testing.T) {
+	t.Run("fan out across emitters", func(t *testing.T) {
+		transports := newMemTransportGroup(2)
+		em1 := New[Subscriber[any]](&Config{Transport: transports[0]})
+		em2 := New[Subscriber[any]](&Config{Transport: transports[1]})
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		suber2 := em2.NewSubscriber()
+		em2.Subscribe(suber2, "topic1", func(subscriber Subscriber[any], msg any) error {
+			assert.Equal(t, msg, "hello")
+			wg.Done()
+			return nil
+		})
+
+		em1.Publish("topic1", "hello")
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("message was not fanned out through the transport")
+		}
+	})
+
+	t.Run("default transport is local", func(t *testing.T) {
+		em := New[Subscriber[any]](nil)
+		_, ok := em.conf.Transport.(*LocalTransport)
+		assert.True(t, ok)
+	})
+}
@@ -6,15 +6,34 @@ import (
 	"time"
 )
 
-type eventCallback[T Subscriber[T]] func(suber T, msg any)
+type eventCallback[T Subscriber[T]] func(suber T, msg any) error
 
+// dedupEntry 是 bucket 去重FIFO队列里的一条记录
+// dedupEntry is one record in a bucket's dedup FIFO queue
+type dedupEntry struct {
+	id        string
+	expiresAt time.Time
+}
+
+// topicField 按 subject 做了二级分区, Publish(topic, subject, msg) 只需遍历命中的 subject 分组
+// topicField is partitioned two levels deep by subject; Publish only walks the matching group
 type topicField[T Subscriber[T]] struct {
-	subers map[string]topicElement[T]
+	subers map[string]map[string]topicElement[T]
+
+	// history 是该主题的环形重放缓冲区, historySeq 为最近一次发布分配的序号(首次发布前为0)
+	// history is the topic's replay ring buffer; historySeq is the last sequence number assigned
+	// (0 before the first publish)
+	history    []historyEntry
+	historySeq uint64
 }
 
 type topicElement[T Subscriber[T]] struct {
 	suber T
 	cb    eventCallback[T]
+
+	// queue 非空时投递走该订阅者的有界队列, 为空时按原有方式同步调用 cb
+	// When queue is non-nil, delivery goes through it; when nil, cb runs synchronously as before
+	queue *deliveryQueue[T]
 }
 
 type (
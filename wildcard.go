@@ -0,0 +1,216 @@
+package event_emitter
+
+import (
+	"strings"
+	"sync"
+)
+
+const (
+	wildcardPlus = "+"
+	wildcardHash = "#"
+	topicSep     = "/"
+)
+
+// isWildcardTopic 判断主题是否包含通配符
+// Determines whether a topic contains a wildcard character (`+` or `#`) anywhere
+func isWildcardTopic(topic string) bool {
+	return strings.ContainsAny(topic, wildcardPlus+wildcardHash)
+}
+
+func splitTopic(topic string) []string {
+	return strings.Split(topic, topicSep)
+}
+
+// trieNode 主题树的一个节点, 按分隔符切分的每一段对应一层
+// A node of the topic trie; each slash-delimited segment corresponds to one level
+type trieNode[T Subscriber[T]] struct {
+	children map[string]*trieNode[T]
+	plus     *trieNode[T]
+	hash     *trieNode[T]
+	subers   map[string]topicElement[T]
+}
+
+func newTrieNode[T Subscriber[T]]() *trieNode[T] {
+	return &trieNode[T]{children: make(map[string]*trieNode[T])}
+}
+
+func (n *trieNode[T]) isEmpty() bool {
+	return len(n.subers) == 0 && len(n.children) == 0 && n.plus == nil && n.hash == nil
+}
+
+func (n *trieNode[T]) insert(segments []string, idx int, subID string, ele topicElement[T]) {
+	if idx == len(segments) {
+		if n.subers == nil {
+			n.subers = make(map[string]topicElement[T])
+		}
+		n.subers[subID] = ele
+		return
+	}
+
+	switch segments[idx] {
+	case wildcardHash:
+		if n.hash == nil {
+			n.hash = newTrieNode[T]()
+		}
+		if n.hash.subers == nil {
+			n.hash.subers = make(map[string]topicElement[T])
+		}
+		n.hash.subers[subID] = ele
+	case wildcardPlus:
+		if n.plus == nil {
+			n.plus = newTrieNode[T]()
+		}
+		n.plus.insert(segments, idx+1, subID, ele)
+	default:
+		child, ok := n.children[segments[idx]]
+		if !ok {
+			child = newTrieNode[T]()
+			n.children[segments[idx]] = child
+		}
+		child.insert(segments, idx+1, subID, ele)
+	}
+}
+
+// remove 从节点上删除订阅者, 返回该节点在删除后是否可以被父节点剪除
+// Removes a subscriber from the node, reports whether the node can now be pruned by its parent
+func (n *trieNode[T]) remove(segments []string, idx int, subID string) bool {
+	if idx == len(segments) {
+		if ele, ok := n.subers[subID]; ok && ele.queue != nil {
+			// 异步关闭, 避免等一个阻塞中的 enqueue 时卡住 trie 锁
+			// close async, so waiting out an in-flight enqueue doesn't stall the trie lock
+			go ele.queue.close()
+		}
+		delete(n.subers, subID)
+		return n.isEmpty()
+	}
+
+	switch segments[idx] {
+	case wildcardHash:
+		if n.hash != nil {
+			if ele, ok := n.hash.subers[subID]; ok && ele.queue != nil {
+				go ele.queue.close()
+			}
+			delete(n.hash.subers, subID)
+			if n.hash.isEmpty() {
+				n.hash = nil
+			}
+		}
+	case wildcardPlus:
+		if n.plus != nil && n.plus.remove(segments, idx+1, subID) {
+			n.plus = nil
+		}
+	default:
+		if child, ok := n.children[segments[idx]]; ok && child.remove(segments, idx+1, subID) {
+			delete(n.children, segments[idx])
+		}
+	}
+	return n.isEmpty()
+}
+
+// collect 把能匹配上这条路径的订阅者都追加到 out 里, 不调用它们的回调
+// collect appends every subscriber matching this path to out without invoking their callbacks
+func (n *trieNode[T]) collect(segments []string, idx int, out *[]topicElement[T]) {
+	if n.hash != nil {
+		for _, v := range n.hash.subers {
+			*out = append(*out, v)
+		}
+	}
+
+	if idx == len(segments) {
+		for _, v := range n.subers {
+			*out = append(*out, v)
+		}
+		return
+	}
+
+	if child, ok := n.children[segments[idx]]; ok {
+		child.collect(segments, idx+1, out)
+	}
+	if n.plus != nil {
+		n.plus.collect(segments, idx+1, out)
+	}
+}
+
+// find 按照模式原样定位节点, 用于精确匹配某个订阅模式
+// Locates a node by walking the pattern literally, used to match a subscription pattern exactly
+func (n *trieNode[T]) find(segments []string, idx int) *trieNode[T] {
+	if idx == len(segments) {
+		return n
+	}
+	switch segments[idx] {
+	case wildcardHash:
+		return n.hash
+	case wildcardPlus:
+		if n.plus == nil {
+			return nil
+		}
+		return n.plus.find(segments, idx+1)
+	default:
+		child, ok := n.children[segments[idx]]
+		if !ok {
+			return nil
+		}
+		return child.find(segments, idx+1)
+	}
+}
+
+// topicTrie 按主题层级路由通配符订阅, 与分片哈希表并行存在
+// Routes wildcard subscriptions by topic hierarchy, kept alongside the sharded hash map
+type topicTrie[T Subscriber[T]] struct {
+	sync.RWMutex
+	root *trieNode[T]
+}
+
+func newTopicTrie[T Subscriber[T]]() *topicTrie[T] {
+	return &topicTrie[T]{root: newTrieNode[T]()}
+}
+
+func (t *topicTrie[T]) subscribe(suber T, topic string, ele topicElement[T]) {
+	t.Lock()
+	defer t.Unlock()
+	t.root.insert(splitTopic(topic), 0, suber.GetSubscriberID(), ele)
+}
+
+func (t *topicTrie[T]) unSubscribe(suber T, topic string) {
+	t.Lock()
+	defer t.Unlock()
+	t.root.remove(splitTopic(topic), 0, suber.GetSubscriberID())
+}
+
+// match 在持锁期间收集出匹配这个主题的全部订阅者, 调用方应在锁外再调用它们的回调
+// match collects every subscriber matching this topic while the lock is held; the caller should
+// invoke callbacks only after releasing it
+func (t *topicTrie[T]) match(topic string) []topicElement[T] {
+	t.RLock()
+	defer t.RUnlock()
+	var recipients []topicElement[T]
+	t.root.collect(splitTopic(topic), 0, &recipients)
+	return recipients
+}
+
+func (t *topicTrie[T]) publish(topic string, msg any) {
+	for _, v := range t.match(topic) {
+		v.cb(v.suber, msg)
+	}
+}
+
+func (t *topicTrie[T]) publishE(topic string, msg any,
+	checkSent func(subscriber T) bool,
+	f func(subscriber T, err error)) {
+	for _, v := range t.match(topic) {
+		if !checkSent(v.suber) {
+			err := v.cb(v.suber, msg)
+			f(v.suber, err)
+		}
+	}
+}
+
+func (t *topicTrie[T]) countByPattern(pattern string) int {
+	t.RLock()
+	defer t.RUnlock()
+	node := t.root.find(splitTopic(pattern), 0)
+	if node == nil {
+		return 0
+	}
+	return len(node.subers)
+}
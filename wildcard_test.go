@@ -0,0 +1,81 @@
+package event_emitter
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventEmitter_Wildcard(t *testing.T) {
+	t.Run("plus", func(t *testing.T) {
+		var em = New[Subscriber[any]](nil)
+		var wg = &sync.WaitGroup{}
+		wg.Add(1)
+
+		suber1 := em.NewSubscriber()
+		em.Subscribe(suber1, "chat/+/news", func(subscriber Subscriber[any], msg any) error {
+			wg.Done()
+			return nil
+		})
+
+		em.Publish("chat/room1/news", "hello")
+		em.Publish("chat/room1/room2/news", "should not match")
+		wg.Wait()
+
+		assert.Equal(t, em.CountSubscriberByTopic("chat/+/news"), 1)
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		var em = New[Subscriber[any]](nil)
+		var wg = &sync.WaitGroup{}
+		wg.Add(2)
+
+		suber1 := em.NewSubscriber()
+		em.Subscribe(suber1, "chat/#", func(subscriber Subscriber[any], msg any) error {
+			wg.Done()
+			return nil
+		})
+
+		em.Publish("chat/room1", "hello")
+		em.Publish("chat/room1/news", "world")
+		wg.Wait()
+
+		assert.Equal(t, em.CountSubscriberByTopic("chat/#"), 1)
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		var em = New[Subscriber[any]](nil)
+		var suber1 = em.NewSubscriber()
+		em.Subscribe(suber1, "chat/+/news", func(subscriber Subscriber[any], msg any) error { return nil })
+		assert.Equal(t, em.CountSubscriberByTopic("chat/+/news"), 1)
+
+		em.UnSubscribe(suber1, "chat/+/news")
+		assert.Equal(t, em.CountSubscriberByTopic("chat/+/news"), 0)
+	})
+
+	t.Run("unsubscribe all", func(t *testing.T) {
+		var em = New[Subscriber[any]](nil)
+		var suber1 = em.NewSubscriber()
+		em.Subscribe(suber1, "chat/+/news", func(subscriber Subscriber[any], msg any) error { return nil })
+		em.Subscribe(suber1, "topic1", func(subscriber Subscriber[any], msg any) error { return nil })
+
+		em.UnSubscribeAll(suber1)
+		assert.Equal(t, em.CountSubscriberByTopic("chat/+/news"), 0)
+		assert.Equal(t, em.CountSubscriberByTopic("topic1"), 0)
+	})
+
+	t.Run("PublishE reaches wildcard subscribers", func(t *testing.T) {
+		var em = New[Subscriber[any]](nil)
+		var hits int
+
+		suber1 := em.NewSubscriber()
+		em.Subscribe(suber1, "chat/+/news", func(subscriber Subscriber[any], msg any) error { return nil })
+
+		em.PublishE("chat/room1/news", "hello",
+			func(subscriber Subscriber[any]) bool { return false },
+			func(subscriber Subscriber[any], err error) { hits++ })
+
+		assert.Equal(t, 1, hits)
+	})
+}